@@ -6,44 +6,235 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"image"
+	"image/jpeg"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/karalabe/go-threema"
 	"github.com/sloppy-claw/openclaw-threema/pkg/bridge"
+	"github.com/sloppy-claw/openclaw-threema/pkg/bridge/media"
+	"github.com/sloppy-claw/openclaw-threema/pkg/events"
+	"github.com/sloppy-claw/openclaw-threema/pkg/truststore"
 )
 
 // Bridge manages the Threema connection and stdio communication
 type Bridge struct {
-	mu       sync.RWMutex
-	id       *threema.Identity
-	conn     *threema.Connection
-	output   chan *bridge.Event
-	shutdown chan struct{}
-	wg       sync.WaitGroup
+	mu        sync.RWMutex
+	id        *threema.Identity
+	conn      *threema.Connection
+	output    chan *bridge.Event
+	responses chan *bridge.Response
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
 
 	// Connection state
 	backup   string
 	password string
+
+	lookupCache *lookupCache
+
+	// trust persists Threema ID -> public key relationships across
+	// restarts (TOFU). blocked tracks IDs whose stored key conflicts with
+	// one seen in an incoming send, until an explicit trust command
+	// resolves it.
+	trust     truststore.Store
+	blockedMu sync.Mutex
+	blocked   map[string]struct{}
+
+	// audit is the structured event chain; defaults to events.Discard{}
+	// so the bridge is silent on this front unless configured otherwise.
+	audit events.Emitter
+
+	// legacyProto speaks the original ad-hoc line-JSON protocol (Command/
+	// Event) instead of JSON-RPC 2.0, for callers mid-migration.
+	legacyProto    bool
+	requestTimeout time.Duration
+	rpc            *bridge.Server
 }
 
 // NewBridge creates a new bridge instance
 func NewBridge() *Bridge {
 	return &Bridge{
-		output:   make(chan *bridge.Event, 100),
-		shutdown: make(chan struct{}),
+		output:         make(chan *bridge.Event, 100),
+		responses:      make(chan *bridge.Response, 100),
+		shutdown:       make(chan struct{}),
+		lookupCache:    newLookupCache(defaultLookupCachePath()),
+		blocked:        make(map[string]struct{}),
+		audit:          events.Discard{},
+		requestTimeout: 30 * time.Second,
+	}
+}
+
+// defaultTrustStorePath returns the on-disk path for the default ("file")
+// trust store backend.
+func defaultTrustStorePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "threema-trust-store.json"
+	}
+	return filepath.Join(dir, "openclaw-threema", "trust-store.json")
+}
+
+// emitAudit records a structured audit event via the configured emitter chain.
+func (b *Bridge) emitAudit(code string, threemaID, peer string, success bool, reason string) {
+	b.audit.EmitAuditEvent(context.Background(), events.AuditEvent{
+		Time:      time.Now(),
+		Code:      code,
+		ThreemaID: threemaID,
+		Peer:      peer,
+		Success:   success,
+		Reason:    reason,
+	})
+}
+
+// defaultLookupCachePath returns the on-disk path for the directory lookup cache.
+func defaultLookupCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "threema-lookup-cache.json"
+	}
+	return filepath.Join(dir, "openclaw-threema", "lookup-cache.json")
+}
+
+// lookupCache is a small on-disk cache of Threema ID -> public key, so
+// repeated lookups don't hammer the directory service.
+type lookupCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]string
+}
+
+// newLookupCache creates a lookup cache backed by the given file path,
+// loading any existing entries from disk.
+func newLookupCache(path string) *lookupCache {
+	c := &lookupCache{
+		path:    path,
+		entries: make(map[string]string),
+	}
+	c.load()
+	return c
+}
+
+func (c *lookupCache) load() {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("WARNING: ignoring corrupt lookup cache at %s: %v", c.path, err)
+		return
+	}
+	c.entries = entries
+}
+
+func (c *lookupCache) get(threemaID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pubkey, ok := c.entries[threemaID]
+	return pubkey, ok
+}
+
+func (c *lookupCache) put(threemaID, pubkey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[threemaID] = pubkey
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		log.Printf("WARNING: failed to create lookup cache dir: %v", err)
+		return
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		log.Printf("WARNING: failed to marshal lookup cache: %v", err)
+		return
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		log.Printf("WARNING: failed to write lookup cache: %v", err)
 	}
 }
 
+// isBlocked reports whether sends to a Threema ID are blocked pending
+// resolution of a trust conflict.
+func (b *Bridge) isBlocked(id string) bool {
+	b.blockedMu.Lock()
+	defer b.blockedMu.Unlock()
+	_, blocked := b.blocked[id]
+	return blocked
+}
+
+func (b *Bridge) setBlocked(id string) {
+	b.blockedMu.Lock()
+	defer b.blockedMu.Unlock()
+	b.blocked[id] = struct{}{}
+}
+
+func (b *Bridge) clearBlocked(id string) {
+	b.blockedMu.Lock()
+	defer b.blockedMu.Unlock()
+	delete(b.blocked, id)
+}
+
+// applyTrust reconciles an incoming public key for a Threema ID against the
+// trust store. On first sight of an ID it records the key (TOFU) and emits
+// trust_changed. A key that disagrees with one already on file is a
+// conflict: emits trust_conflict and blocks further sends to that ID,
+// unless explicit is true (the operator issued a trust command), in which
+// case the new key always wins and any existing block is cleared.
+func (b *Bridge) applyTrust(to, pubkey string, explicit bool) error {
+	if b.trust == nil || pubkey == "" {
+		return nil
+	}
+
+	entry, err := b.trust.Get(to)
+	if errors.Is(err, truststore.ErrNotFound) {
+		if err := b.trust.Put(to, pubkey); err != nil {
+			return fmt.Errorf("failed to record trust for %s: %w", to, err)
+		}
+		b.emit(bridge.NewTrustChangedEvent(to, pubkey))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read trust store for %s: %w", to, err)
+	}
+
+	if entry.Pubkey == pubkey {
+		if explicit {
+			b.clearBlocked(to)
+		}
+		return nil
+	}
+
+	if !explicit {
+		b.setBlocked(to)
+		b.emit(bridge.NewTrustConflictEvent(to, pubkey, entry.Pubkey))
+		return fmt.Errorf("public key for %s conflicts with the trusted key; use the trust command to override", to)
+	}
+
+	if err := b.trust.Put(to, pubkey); err != nil {
+		return fmt.Errorf("failed to record trust for %s: %w", to, err)
+	}
+	b.clearBlocked(to)
+	b.emit(bridge.NewTrustChangedEvent(to, pubkey))
+	return nil
+}
+
 // emit sends an event to the TypeScript process
 func (b *Bridge) emit(event *bridge.Event) {
 	select {
@@ -55,8 +246,11 @@ func (b *Bridge) emit(event *bridge.Event) {
 	}
 }
 
-// handleConnect processes a connect command
-func (b *Bridge) handleConnect(cmd *bridge.Command) error {
+// doConnect loads an identity from a Threema ID backup and connects to the
+// Threema servers. On success it always emits a "connected" notification
+// (also true for reconnects, which have no request to correlate a response
+// to) in addition to returning a result for a correlated JSON-RPC response.
+func (b *Bridge) doConnect(backup, password string) (*bridge.ConnectResult, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -67,13 +261,14 @@ func (b *Bridge) handleConnect(cmd *bridge.Command) error {
 	}
 
 	// Store credentials for reconnection
-	b.backup = cmd.Backup
-	b.password = cmd.Password
+	b.backup = backup
+	b.password = password
 
 	// Load identity from backup
-	id, err := threema.Identify(cmd.Backup, cmd.Password)
+	id, err := threema.Identify(backup, password)
 	if err != nil {
-		return fmt.Errorf("failed to load identity: %w", err)
+		b.emitAudit(events.CodeConnect, "", "", false, err.Error())
+		return nil, fmt.Errorf("failed to load identity: %w", err)
 	}
 	b.id = id
 
@@ -83,14 +278,22 @@ func (b *Bridge) handleConnect(cmd *bridge.Command) error {
 	// Connect to Threema servers
 	conn, err := threema.Connect(id, handler)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		b.emitAudit(events.CodeConnect, id.Self(), "", false, err.Error())
+		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 	b.conn = conn
 
 	// Emit connected event
 	b.emit(bridge.NewConnectedEvent(id.Self()))
+	b.emitAudit(events.CodeConnect, id.Self(), "", true, "")
 
-	return nil
+	return &bridge.ConnectResult{ID: id.Self()}, nil
+}
+
+// handleConnect processes a legacy connect command.
+func (b *Bridge) handleConnect(cmd *bridge.Command) error {
+	_, err := b.doConnect(cmd.Backup, cmd.Password)
+	return err
 }
 
 // createHandler creates a Threema event handler
@@ -98,16 +301,29 @@ func (b *Bridge) createHandler() *threema.Handler {
 	return &threema.Handler{
 		Message: func(from string, nick string, when time.Time, msg string) {
 			b.emit(bridge.NewMessageEvent(from, nick, when, msg))
+			b.emitAudit(events.CodeMessage, "", from, true, "")
+		},
+		Image: func(from, nick string, when time.Time, img, thumb image.Image, caption string) {
+			path, err := persistIncomingImage(img)
+			if err != nil {
+				log.Printf("failed to persist incoming image from %s: %v", from, err)
+				b.emitAudit(events.CodeError, "", from, false, err.Error())
+				return
+			}
+			b.emit(bridge.NewFileMessageEvent(from, nick, when, path, "image/jpeg", caption))
+			b.emitAudit(events.CodeMessage, "", from, true, "")
 		},
 		Spam: func(from string, nick string, when time.Time) {
 			// Log spam but don't forward - could add spam event if needed
 			log.Printf("Spam from untrusted contact: %s (%s)", from, nick)
+			b.emitAudit(events.CodeSpamDrop, "", from, false, "untrusted contact")
 		},
 		Alert: func(reason string) {
 			log.Printf("Threema alert: %s", reason)
 		},
 		Error: func(reason string, reconnect bool) {
 			b.emit(bridge.NewErrorEvent(fmt.Errorf("threema error: %s (reconnect=%v)", reason, reconnect)))
+			b.emitAudit(events.CodeError, "", "", false, reason)
 			if reconnect {
 				b.scheduleReconnect()
 			}
@@ -119,6 +335,21 @@ func (b *Bridge) createHandler() *threema.Handler {
 	}
 }
 
+// persistIncomingImage JPEG-encodes a decoded incoming image and streams it
+// to a temp file via pkg/bridge/media, so the bridge only ever hands the
+// TypeScript side a path, never the raw image bytes inline.
+func persistIncomingImage(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		return "", fmt.Errorf("failed to encode received image: %w", err)
+	}
+	path, err := media.WriteTemp(&buf, "threema-received-*.jpg")
+	if err != nil {
+		return "", fmt.Errorf("failed to stage received image: %w", err)
+	}
+	return path, nil
+}
+
 // scheduleReconnect attempts to reconnect with exponential backoff
 func (b *Bridge) scheduleReconnect() {
 	b.wg.Add(1)
@@ -143,6 +374,7 @@ func (b *Bridge) scheduleReconnect() {
 
 			delay := delays[min(attempt, len(delays)-1)]
 			log.Printf("Reconnecting in %v (attempt %d)...", delay, attempt+1)
+			b.emitAudit(events.CodeReconnectAttempt, "", "", true, fmt.Sprintf("attempt %d", attempt+1))
 
 			select {
 			case <-time.After(delay):
@@ -162,6 +394,7 @@ func (b *Bridge) scheduleReconnect() {
 			if err != nil {
 				b.mu.Unlock()
 				log.Printf("Failed to reload identity: %v", err)
+				b.emitAudit(events.CodeReconnectFailure, "", "", false, err.Error())
 				continue
 			}
 			b.id = id
@@ -171,6 +404,7 @@ func (b *Bridge) scheduleReconnect() {
 			if err != nil {
 				b.mu.Unlock()
 				log.Printf("Failed to reconnect: %v", err)
+				b.emitAudit(events.CodeReconnectFailure, id.Self(), "", false, err.Error())
 				continue
 			}
 
@@ -179,31 +413,40 @@ func (b *Bridge) scheduleReconnect() {
 
 			log.Println("Reconnected successfully")
 			b.emit(bridge.NewConnectedEvent(id.Self()))
+			b.emitAudit(events.CodeReconnectSuccess, id.Self(), "", true, "")
 			return
 		}
 	}()
 }
 
-// handleSend processes a send command
-func (b *Bridge) handleSend(cmd *bridge.Command) error {
+// doSend sends a text message, trusting the recipient first if a pubkey is
+// given and they aren't already trusted.
+func (b *Bridge) doSend(to, pubkey, text string) error {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	if b.conn == nil {
-		return errors.New("not connected")
-	}
-
-	if cmd.To == "" {
+	if to == "" {
 		return errors.New("missing 'to' field")
 	}
 
-	if cmd.Text == "" {
+	if text == "" {
 		return errors.New("missing 'text' field")
 	}
 
+	if b.isBlocked(to) {
+		return fmt.Errorf("sends to %s are blocked by an unresolved trust conflict", to)
+	}
+
+	if b.conn == nil {
+		return errors.New("not connected")
+	}
+
 	// Trust the recipient if pubkey provided and not already trusted
-	if cmd.Pubkey != "" {
-		if err := b.id.Trust(cmd.To, cmd.Pubkey); err != nil {
+	if pubkey != "" {
+		if err := b.applyTrust(to, pubkey, false); err != nil {
+			return err
+		}
+		if err := b.id.Trust(to, pubkey); err != nil {
 			// Ignore "contact already exists" error
 			if err.Error() != "contact already exists" {
 				return fmt.Errorf("failed to trust recipient: %w", err)
@@ -212,15 +455,73 @@ func (b *Bridge) handleSend(cmd *bridge.Command) error {
 	}
 
 	// Send the message
-	if err := b.conn.SendText(cmd.To, cmd.Text); err != nil {
+	if err := b.conn.SendText(to, text); err != nil {
+		b.emitAudit(events.CodeSend, "", to, false, err.Error())
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
+	b.emitAudit(events.CodeSend, "", to, true, "")
 	return nil
 }
 
-// handleTrust processes a trust command
-func (b *Bridge) handleTrust(cmd *bridge.Command) error {
+// handleSend processes a legacy send command.
+func (b *Bridge) handleSend(cmd *bridge.Command) error {
+	return b.doSend(cmd.To, cmd.Pubkey, cmd.Text)
+}
+
+// doSendFile sends an image attachment, either read from a path already on
+// disk or decoded from a base64 blob (small attachments only, see
+// pkg/bridge/media). The underlying Threema client only transfers images
+// (threema.Connection.SendImage); there is no generic file transfer in the
+// protocol it speaks.
+func (b *Bridge) doSendFile(to, path, data, caption string) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if to == "" {
+		return errors.New("missing 'to' field")
+	}
+	if path == "" && data == "" {
+		return errors.New("one of 'path' or 'data' is required")
+	}
+	if b.isBlocked(to) {
+		return fmt.Errorf("sends to %s are blocked by an unresolved trust conflict", to)
+	}
+	if b.conn == nil {
+		return errors.New("not connected")
+	}
+
+	var blob []byte
+	if path != "" {
+		var err error
+		blob, err = os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read attachment: %w", err)
+		}
+	} else {
+		var err error
+		blob, err = base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode attachment: %w", err)
+		}
+	}
+
+	if err := b.conn.SendImage(to, blob, caption); err != nil {
+		b.emitAudit(events.CodeSend, "", to, false, err.Error())
+		return fmt.Errorf("failed to send image: %w", err)
+	}
+
+	b.emitAudit(events.CodeSend, "", to, true, "")
+	return nil
+}
+
+// handleSendFile processes a legacy send_file command.
+func (b *Bridge) handleSendFile(cmd *bridge.Command) error {
+	return b.doSendFile(cmd.To, cmd.Path, cmd.Data, cmd.Caption)
+}
+
+// doTrust marks a Threema ID as trusted with the given public key.
+func (b *Bridge) doTrust(to, pubkey string) error {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -228,24 +529,65 @@ func (b *Bridge) handleTrust(cmd *bridge.Command) error {
 		return errors.New("not connected")
 	}
 
-	if cmd.To == "" {
+	if to == "" {
 		return errors.New("missing 'to' field (Threema ID)")
 	}
 
-	if cmd.Pubkey == "" {
+	if pubkey == "" {
 		return errors.New("missing 'pubkey' field (base64 public key)")
 	}
 
-	if err := b.id.Trust(cmd.To, cmd.Pubkey); err != nil {
+	// An explicit trust command always wins over the trust store, even if
+	// it disagrees with a previously trusted key, and clears any block.
+	if err := b.applyTrust(to, pubkey, true); err != nil {
+		b.emitAudit(events.CodeTrust, "", to, false, err.Error())
+		return err
+	}
+
+	if err := b.id.Trust(to, pubkey); err != nil {
 		// Ignore "contact already exists" error
 		if err.Error() != "contact already exists" {
+			b.emitAudit(events.CodeTrust, "", to, false, err.Error())
 			return fmt.Errorf("failed to trust contact: %w", err)
 		}
 	}
 
+	b.emitAudit(events.CodeTrust, "", to, true, "")
 	return nil
 }
 
+// handleTrust processes a legacy trust command.
+func (b *Bridge) handleTrust(cmd *bridge.Command) error {
+	return b.doTrust(cmd.To, cmd.Pubkey)
+}
+
+// doLookup resolves a Threema ID to its public key via the directory
+// service, consulting the on-disk cache first.
+func (b *Bridge) doLookup(ctx context.Context, to string) (*bridge.LookupResult, error) {
+	if to == "" {
+		return nil, errors.New("missing 'to' field (Threema ID)")
+	}
+
+	pubkey, ok := b.lookupCache.get(to)
+	if !ok {
+		var err error
+		pubkey, err = LookupPublicKey(ctx, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup %s: %w", to, err)
+		}
+		b.lookupCache.put(to, pubkey)
+	}
+
+	b.emit(bridge.NewLookupResultEvent(to, pubkey))
+	return &bridge.LookupResult{ThreemaID: to, Pubkey: pubkey}, nil
+}
+
+// handleLookup processes a legacy lookup command.
+func (b *Bridge) handleLookup(cmd *bridge.Command) error {
+	_, err := b.doLookup(context.Background(), cmd.To)
+	return err
+}
+
 // processCommand handles a single command
 func (b *Bridge) processCommand(cmd *bridge.Command) {
 	var err error
@@ -255,8 +597,12 @@ func (b *Bridge) processCommand(cmd *bridge.Command) {
 		err = b.handleConnect(cmd)
 	case bridge.CmdSend:
 		err = b.handleSend(cmd)
+	case bridge.CmdSendFile:
+		err = b.handleSendFile(cmd)
 	case bridge.CmdTrust:
 		err = b.handleTrust(cmd)
+	case bridge.CmdLookup:
+		err = b.handleLookup(cmd)
 	case bridge.CmdPing:
 		b.emit(bridge.NewPongEvent())
 	default:
@@ -268,6 +614,116 @@ func (b *Bridge) processCommand(cmd *bridge.Command) {
 	}
 }
 
+// withTimeout runs fn on its own goroutine and waits for either it to
+// return or ctx to be done, whichever comes first. The go-threema client
+// has no notion of cancellation - its sends and connects are plain blocking
+// calls - so a timed-out fn keeps running in the background until the
+// library itself returns; withTimeout only bounds how long the RPC caller
+// waits for a response, not how long the underlying work takes.
+func withTimeout(ctx context.Context, fn func() error) error {
+	errc := make(chan error, 1)
+	go func() { errc <- fn() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newRPCServer wires each JSON-RPC method to the corresponding domain
+// method on b, decoding params and encoding results/errors along the way.
+// Methods that call into go-threema are run through withTimeout so
+// --request-timeout bounds every method, not just lookup's HTTP call.
+func newRPCServer(b *Bridge) *bridge.Server {
+	s := bridge.NewServer()
+
+	s.Register(bridge.CmdConnect, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var p bridge.ConnectParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &bridge.RPCError{Code: bridge.CodeInvalidParams, Message: err.Error()}
+		}
+		var result *bridge.ConnectResult
+		if err := withTimeout(ctx, func() error {
+			var err error
+			result, err = b.doConnect(p.Backup, p.Password)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+
+	s.Register(bridge.CmdSend, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var p bridge.SendParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &bridge.RPCError{Code: bridge.CodeInvalidParams, Message: err.Error()}
+		}
+		if err := withTimeout(ctx, func() error { return b.doSend(p.To, p.Pubkey, p.Text) }); err != nil {
+			return nil, err
+		}
+		return bridge.AckResult{OK: true}, nil
+	})
+
+	s.Register(bridge.CmdSendFile, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var p bridge.SendFileParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &bridge.RPCError{Code: bridge.CodeInvalidParams, Message: err.Error()}
+		}
+		if err := withTimeout(ctx, func() error { return b.doSendFile(p.To, p.Path, p.Data, p.Caption) }); err != nil {
+			return nil, err
+		}
+		return bridge.AckResult{OK: true}, nil
+	})
+
+	s.Register(bridge.CmdTrust, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var p bridge.TrustParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &bridge.RPCError{Code: bridge.CodeInvalidParams, Message: err.Error()}
+		}
+		if err := withTimeout(ctx, func() error { return b.doTrust(p.To, p.Pubkey) }); err != nil {
+			return nil, err
+		}
+		return bridge.AckResult{OK: true}, nil
+	})
+
+	s.Register(bridge.CmdLookup, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var p bridge.LookupParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &bridge.RPCError{Code: bridge.CodeInvalidParams, Message: err.Error()}
+		}
+		return b.doLookup(ctx, p.To)
+	})
+
+	s.Register(bridge.CmdPing, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		b.emit(bridge.NewPongEvent())
+		return bridge.AckResult{OK: true}, nil
+	})
+
+	return s
+}
+
+// dispatchRPC decodes and runs a single JSON-RPC request line with a
+// per-request timeout, then queues its response (if any) for writeLoop.
+// Running in its own goroutine lets multiple sends be in flight at once.
+func (b *Bridge) dispatchRPC(line []byte) {
+	defer b.wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	resp := b.rpc.Dispatch(ctx, line)
+	if resp == nil {
+		return // true notification; no reply expected
+	}
+
+	select {
+	case b.responses <- resp:
+	case <-b.shutdown:
+	}
+}
+
 // readLoop reads commands from stdin
 func (b *Bridge) readLoop() {
 	defer b.wg.Done()
@@ -288,13 +744,21 @@ func (b *Bridge) readLoop() {
 			continue
 		}
 
-		cmd, err := bridge.ParseCommand(line)
-		if err != nil {
-			b.emit(bridge.NewErrorEvent(fmt.Errorf("invalid command: %w", err)))
+		if b.legacyProto {
+			cmd, err := bridge.ParseCommand(line)
+			if err != nil {
+				b.emit(bridge.NewErrorEvent(fmt.Errorf("invalid command: %w", err)))
+				continue
+			}
+			b.processCommand(cmd)
 			continue
 		}
 
-		b.processCommand(cmd)
+		// scanner reuses its buffer on the next Scan(), so the dispatch
+		// goroutine needs its own copy of the line.
+		lineCopy := append([]byte(nil), line...)
+		b.wg.Add(1)
+		go b.dispatchRPC(lineCopy)
 	}
 
 	if err := scanner.Err(); err != nil && err != io.EOF {
@@ -305,24 +769,43 @@ func (b *Bridge) readLoop() {
 	close(b.shutdown)
 }
 
-// writeLoop writes events to stdout
+// writeLoop writes events and (in JSON-RPC mode) responses to stdout
 func (b *Bridge) writeLoop() {
 	defer b.wg.Done()
 
 	encoder := json.NewEncoder(os.Stdout)
 
+	writeEvent := func(event *bridge.Event) {
+		var err error
+		if b.legacyProto {
+			err = encoder.Encode(event)
+		} else {
+			err = encoder.Encode(bridge.NewNotification(event.Event, event))
+		}
+		if err != nil {
+			log.Printf("stdout write error: %v", err)
+		}
+	}
+	writeResponse := func(resp *bridge.Response) {
+		if err := encoder.Encode(resp); err != nil {
+			log.Printf("stdout write error: %v", err)
+		}
+	}
+
 	for {
 		select {
 		case event := <-b.output:
-			if err := encoder.Encode(event); err != nil {
-				log.Printf("stdout write error: %v", err)
-			}
+			writeEvent(event)
+		case resp := <-b.responses:
+			writeResponse(resp)
 		case <-b.shutdown:
-			// Drain remaining events
+			// Drain remaining events and responses
 			for {
 				select {
 				case event := <-b.output:
-					encoder.Encode(event)
+					writeEvent(event)
+				case resp := <-b.responses:
+					writeResponse(resp)
 				default:
 					return
 				}
@@ -350,6 +833,7 @@ func (b *Bridge) Run() {
 
 	// Wait for shutdown
 	<-b.shutdown
+	b.emitAudit(events.CodeShutdown, "", "", true, "")
 
 	// Close connection
 	b.mu.Lock()
@@ -358,15 +842,26 @@ func (b *Bridge) Run() {
 	}
 	b.mu.Unlock()
 
+	if b.trust != nil {
+		if err := b.trust.Close(); err != nil {
+			log.Printf("WARNING: failed to close trust store: %v", err)
+		}
+	}
+
 	// Wait for goroutines
 	b.wg.Wait()
 }
 
 // LookupPublicKey fetches a public key from Threema's directory service
-func LookupPublicKey(threemaID string) (string, error) {
+func LookupPublicKey(ctx context.Context, threemaID string) (string, error) {
 	url := fmt.Sprintf("https://api.threema.ch/identity/%s", threemaID)
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build lookup request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to lookup public key: %w", err)
 	}
@@ -387,10 +882,69 @@ func LookupPublicKey(threemaID string) (string, error) {
 	return result.PublicKey, nil
 }
 
+// buildAuditChain assembles the active audit emitter chain from CLI flags.
+// An empty --audit-emitters leaves the bridge silent on this front
+// (events.Discard{}), matching pre-existing behavior.
+func buildAuditChain(emitterList, auditLogPath string) (events.Emitter, error) {
+	var chain events.Chain
+	for _, name := range strings.Split(emitterList, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+			continue
+		case "stdout":
+			chain = append(chain, events.NewStdoutEmitter())
+		case "filelog":
+			if auditLogPath == "" {
+				return nil, errors.New("--audit-log-path is required when \"filelog\" is in --audit-emitters")
+			}
+			f, err := events.NewFileLog(auditLogPath)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, f)
+		case "discard":
+			chain = append(chain, events.Discard{})
+		default:
+			return nil, fmt.Errorf("unknown audit emitter %q", name)
+		}
+	}
+	if len(chain) == 0 {
+		return events.Discard{}, nil
+	}
+	return chain, nil
+}
+
 func main() {
+	auditEmitters := flag.String("audit-emitters", "", "comma-separated audit backends to enable: stdout (writes to stderr, since real stdout is the JSON-RPC protocol stream), filelog, discard")
+	auditLogPath := flag.String("audit-log-path", "", "file path for the \"filelog\" audit backend")
+	legacyProto := flag.Bool("legacy-proto", false, "speak the pre-JSON-RPC line protocol instead of JSON-RPC 2.0 (transitional, removed after one release)")
+	requestTimeout := flag.Duration("request-timeout", 30*time.Second, "per-request timeout for the JSON-RPC protocol")
+	trustStoreBackend := flag.String("trust-store-backend", "file", "trust store backend: file or bolt")
+	trustStoreDSN := flag.String("trust-store-dsn", "", "trust store location: file/bolt path (defaults to a cache-dir path for \"file\")")
+	flag.Parse()
+
 	log.SetPrefix("[threema-bridge] ")
 	log.SetFlags(log.Ltime | log.Lmicroseconds)
 
+	audit, err := buildAuditChain(*auditEmitters, *auditLogPath)
+	if err != nil {
+		log.Fatalf("invalid audit configuration: %v", err)
+	}
+
+	dsn := *trustStoreDSN
+	if dsn == "" && *trustStoreBackend == "file" {
+		dsn = defaultTrustStorePath()
+	}
+	trust, err := truststore.Open(*trustStoreBackend, dsn)
+	if err != nil {
+		log.Fatalf("invalid trust store configuration: %v", err)
+	}
+
 	bridge := NewBridge()
+	bridge.audit = audit
+	bridge.legacyProto = *legacyProto
+	bridge.requestTimeout = *requestTimeout
+	bridge.trust = trust
+	bridge.rpc = newRPCServer(bridge)
 	bridge.Run()
 }
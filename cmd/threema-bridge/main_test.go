@@ -0,0 +1,98 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sloppy-claw/openclaw-threema/pkg/truststore"
+)
+
+// newTestBridge returns a Bridge with a fresh on-disk trust store, wired up
+// enough to exercise applyTrust/isBlocked/doSend/doSendFile without a live
+// Threema connection.
+func newTestBridge(t *testing.T) *Bridge {
+	t.Helper()
+	store, err := truststore.Open("file", filepath.Join(t.TempDir(), "trust.json"))
+	if err != nil {
+		t.Fatalf("truststore.Open() error = %v", err)
+	}
+	b := NewBridge()
+	b.trust = store
+	return b
+}
+
+func TestApplyTrustFirstSeenRecordsTOFU(t *testing.T) {
+	b := newTestBridge(t)
+
+	if err := b.applyTrust("ABCD1234", "pubkey==", false); err != nil {
+		t.Fatalf("applyTrust() error = %v", err)
+	}
+
+	entry, err := b.trust.Get("ABCD1234")
+	if err != nil {
+		t.Fatalf("trust.Get() error = %v", err)
+	}
+	if entry.Pubkey != "pubkey==" {
+		t.Errorf("Pubkey = %v, want pubkey==", entry.Pubkey)
+	}
+	if b.isBlocked("ABCD1234") {
+		t.Error("isBlocked() = true after first-seen trust, want false")
+	}
+}
+
+func TestApplyTrustConflictBlocksSends(t *testing.T) {
+	b := newTestBridge(t)
+
+	if err := b.applyTrust("ABCD1234", "key-a", false); err != nil {
+		t.Fatalf("applyTrust() (first seen) error = %v", err)
+	}
+
+	if err := b.applyTrust("ABCD1234", "key-b", false); err == nil {
+		t.Fatal("applyTrust() with a conflicting key error = nil, want an error")
+	}
+	if !b.isBlocked("ABCD1234") {
+		t.Fatal("isBlocked() = false after a conflicting key, want true")
+	}
+
+	if err := b.doSend("ABCD1234", "", "hello"); err == nil {
+		t.Error("doSend() to a blocked ID error = nil, want an error")
+	}
+	if err := b.doSendFile("ABCD1234", "", "aGVsbG8=", ""); err == nil {
+		t.Error("doSendFile() to a blocked ID error = nil, want an error")
+	}
+}
+
+func TestApplyTrustExplicitOverrideClearsBlock(t *testing.T) {
+	b := newTestBridge(t)
+
+	if err := b.applyTrust("ABCD1234", "key-a", false); err != nil {
+		t.Fatalf("applyTrust() (first seen) error = %v", err)
+	}
+	if err := b.applyTrust("ABCD1234", "key-b", false); err == nil {
+		t.Fatal("applyTrust() with a conflicting key error = nil, want an error")
+	}
+	if !b.isBlocked("ABCD1234") {
+		t.Fatal("isBlocked() = false after a conflicting key, want true")
+	}
+
+	if err := b.applyTrust("ABCD1234", "key-b", true); err != nil {
+		t.Fatalf("applyTrust() (explicit override) error = %v", err)
+	}
+	if b.isBlocked("ABCD1234") {
+		t.Error("isBlocked() = true after explicit trust override, want false")
+	}
+
+	entry, err := b.trust.Get("ABCD1234")
+	if err != nil {
+		t.Fatalf("trust.Get() error = %v", err)
+	}
+	if entry.Pubkey != "key-b" {
+		t.Errorf("Pubkey = %v, want key-b", entry.Pubkey)
+	}
+
+	// doSend still fails (not connected), but no longer because of the
+	// block - confirms the override actually cleared it.
+	if err := b.doSend("ABCD1234", "", "hello"); err == nil || err.Error() != "not connected" {
+		t.Errorf("doSend() error = %v, want \"not connected\"", err)
+	}
+}
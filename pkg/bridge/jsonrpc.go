@@ -0,0 +1,83 @@
+package bridge
+
+import "encoding/json"
+
+// JSONRPCVersion is the protocol version advertised on every envelope.
+const JSONRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes (see the spec's "Error object" section).
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC 2.0 request envelope from TypeScript to Go. Method
+// is one of the Cmd* constants in protocol.go; Params is the method-specific
+// payload (see params.go), decoded lazily by the registered HandlerFunc.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether this request carries no id, and therefore
+// expects no Response (per the JSON-RPC 2.0 spec).
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// ParseRequest parses a JSON-RPC request from a single line of stdin.
+func ParseRequest(data []byte) (*Request, error) {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// RPCError is a JSON-RPC 2.0 error object.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// Response is a JSON-RPC 2.0 response envelope from Go to TypeScript. Every
+// dispatched Request that carries an id is guaranteed exactly one Response,
+// correlated by ID; notifications get none.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// NewResponse creates a successful response correlated to id.
+func NewResponse(id json.RawMessage, result any) *Response {
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Result: result}
+}
+
+// NewErrorResponse creates a failed response correlated to id.
+func NewErrorResponse(id json.RawMessage, code int, message string) *Response {
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Error: &RPCError{Code: code, Message: message}}
+}
+
+// Notification is a JSON-RPC 2.0 notification: a server-initiated message
+// with no id and therefore no expected reply (connected, message, pong, ...).
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// NewNotification creates a notification for the given method.
+func NewNotification(method string, params any) *Notification {
+	return &Notification{JSONRPC: JSONRPCVersion, Method: method, Params: params}
+}
@@ -0,0 +1,29 @@
+// Package media streams attachment blobs to disk instead of round-tripping
+// them through the bridge's JSON envelope. The stdin scanner that reads
+// commands caps each line at 1 MiB; base64-encoding an attachment into that
+// line would make the buffer the ceiling on attachment size, so callers
+// work with temp file paths instead.
+package media
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// WriteTemp streams r into a new temp file matching pattern (see
+// os.CreateTemp) and returns its path. The caller owns the file and is
+// responsible for removing it once done.
+func WriteTemp(r io.Reader, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,23 @@
+package media
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteTemp(t *testing.T) {
+	path, err := WriteTemp(strings.NewReader("hello attachment"), "media-test-*")
+	if err != nil {
+		t.Fatalf("WriteTemp() error = %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read temp file: %v", err)
+	}
+	if string(data) != "hello attachment" {
+		t.Errorf("content = %q, want %q", data, "hello attachment")
+	}
+}
@@ -0,0 +1,58 @@
+package bridge
+
+// Params types for each Cmd* method's "params" object, and the Result
+// types returned in a successful Response. These replace the single
+// catch-all Command struct once a caller has moved off --legacy-proto.
+
+// ConnectParams are the params for the "connect" method.
+type ConnectParams struct {
+	Backup   string `json:"backup"`
+	Password string `json:"password"`
+}
+
+// ConnectResult is the result of a successful "connect" call.
+type ConnectResult struct {
+	ID string `json:"id"` // Own Threema ID
+}
+
+// SendParams are the params for the "send" method.
+type SendParams struct {
+	To     string `json:"to"`               // 8-char Threema ID
+	Pubkey string `json:"pubkey,omitempty"` // base64 public key, trusts recipient if set
+	Text   string `json:"text"`
+}
+
+// SendFileParams are the params for the "send_file" method. Exactly one of
+// Path or Data should be set; Path is preferred since it avoids round-
+// tripping the attachment through the JSON envelope (see pkg/bridge/media).
+// The underlying Threema client only transfers images, so the blob must
+// decode as one.
+type SendFileParams struct {
+	To      string `json:"to"`
+	Path    string `json:"path,omitempty"`
+	Data    string `json:"data,omitempty"` // base64, small attachments only
+	Caption string `json:"caption,omitempty"`
+}
+
+// TrustParams are the params for the "trust" method.
+type TrustParams struct {
+	To     string `json:"to"`     // 8-char Threema ID
+	Pubkey string `json:"pubkey"` // base64 public key
+}
+
+// LookupParams are the params for the "lookup" method.
+type LookupParams struct {
+	To string `json:"to"` // 8-char Threema ID
+}
+
+// LookupResult is the result of a successful "lookup" call.
+type LookupResult struct {
+	ThreemaID string `json:"threemaId"`
+	Pubkey    string `json:"pubkey"`
+}
+
+// AckResult is the result of a method whose success carries no other data
+// (send, trust, ping).
+type AckResult struct {
+	OK bool `json:"ok"`
+}
@@ -1,5 +1,11 @@
-// Package bridge defines JSON-RPC message types for communication between
-// the TypeScript plugin and the Go Threema bridge.
+// Package bridge defines the message types for communication between the
+// TypeScript plugin and the Go Threema bridge.
+//
+// Command below is the original ad-hoc line-JSON request, kept for the
+// --legacy-proto transition period; new integrations should speak the real
+// JSON-RPC 2.0 envelopes in jsonrpc.go instead, which correlate requests to
+// responses by id. See server.go for the dispatcher. Event is written as-is
+// in legacy mode and as the params of a JSON-RPC notification otherwise.
 package bridge
 
 import (
@@ -7,41 +13,69 @@ import (
 	"time"
 )
 
-// Command types sent from TypeScript to Go
+// Command types sent from TypeScript to Go: the legacy "cmd" values and the
+// JSON-RPC method names.
 const (
-	CmdConnect = "connect"
-	CmdSend    = "send"
-	CmdTrust   = "trust"
-	CmdPing    = "ping"
+	CmdConnect  = "connect"
+	CmdSend     = "send"
+	CmdSendFile = "send_file"
+	CmdTrust    = "trust"
+	CmdLookup   = "lookup"
+	CmdPing     = "ping"
 )
 
-// Event types sent from Go to TypeScript
+// Event types sent from Go to TypeScript: the legacy "event" values and the
+// JSON-RPC notification method names.
 const (
-	EventConnected = "connected"
-	EventMessage   = "message"
-	EventError     = "error"
-	EventPong      = "pong"
+	EventConnected     = "connected"
+	EventMessage       = "message"
+	EventFileMessage   = "file_message"
+	EventError         = "error"
+	EventPong          = "pong"
+	EventLookupResult  = "lookup_result"
+	EventTrustChanged  = "trust_changed"
+	EventTrustConflict = "trust_conflict"
 )
 
-// Command is a message from TypeScript to Go
+// Command is a message from TypeScript to Go.
+//
+// NOTE: group chat and delivery receipts are NOT supported. The
+// github.com/karalabe/go-threema client only exposes SendText/SendImage and
+// a Handler with Message/Image/Spam/Alert/Error/Closed - there is no group
+// send and no delivery-receipt callback to wire up. There is deliberately
+// no CmdSendGroup here; do not build plugin-side commands expecting one.
 type Command struct {
 	Cmd      string `json:"cmd"`
 	Backup   string `json:"backup,omitempty"`   // For connect
 	Password string `json:"password,omitempty"` // For connect
-	To       string `json:"to,omitempty"`       // For send/trust (8-char Threema ID)
+	To       string `json:"to,omitempty"`       // For send/send_file/trust/lookup (8-char Threema ID)
 	Pubkey   string `json:"pubkey,omitempty"`   // For send/trust (base64 public key)
 	Text     string `json:"text,omitempty"`     // For send
+	Path     string `json:"path,omitempty"`     // For send_file (path on disk)
+	Data     string `json:"data,omitempty"`     // For send_file (base64, small attachments only)
+	Caption  string `json:"caption,omitempty"`  // For send_file
 }
 
-// Event is a message from Go to TypeScript
+// Event is a message from Go to TypeScript.
+//
+// NOTE: there is no EventGroupMessage and no EventDeliveryReceipt. Group
+// chat and delivery receipts are not supported by the underlying Threema
+// client (see the NOTE on Command) and never will be emitted; the plugin
+// side must not wait on them.
 type Event struct {
-	Event string `json:"event"`
-	ID    string `json:"id,omitempty"`    // For connected (own Threema ID)
-	From  string `json:"from,omitempty"`  // For message
-	Nick  string `json:"nick,omitempty"`  // For message
-	Time  string `json:"time,omitempty"`  // For message (RFC3339)
-	Text  string `json:"text,omitempty"`  // For message
-	Error string `json:"error,omitempty"` // For error
+	Event      string `json:"event"`
+	ID         string `json:"id,omitempty"`         // For connected (own Threema ID)
+	From       string `json:"from,omitempty"`       // For message/file_message
+	Nick       string `json:"nick,omitempty"`       // For message/file_message
+	Time       string `json:"time,omitempty"`       // For message/file_message (RFC3339)
+	Text       string `json:"text,omitempty"`       // For message
+	Error      string `json:"error,omitempty"`      // For error
+	ThreemaID  string `json:"threemaId,omitempty"`  // For lookup_result/trust_changed/trust_conflict
+	Pubkey     string `json:"pubkey,omitempty"`     // For lookup_result/trust_changed/trust_conflict (base64 public key)
+	PrevPubkey string `json:"prevPubkey,omitempty"` // For trust_conflict (the previously trusted key)
+	Path       string `json:"path,omitempty"`       // For file_message (local path of the received attachment)
+	Mime       string `json:"mime,omitempty"`       // For file_message
+	Caption    string `json:"caption,omitempty"`    // For file_message
 }
 
 // ParseCommand parses a JSON command from stdin
@@ -72,6 +106,21 @@ func NewMessageEvent(from, nick string, when time.Time, text string) *Event {
 	}
 }
 
+// NewFileMessageEvent creates a file_message event. The underlying Threema
+// client only transfers images, so path always points at a locally-written
+// image file, not an arbitrary attachment.
+func NewFileMessageEvent(from, nick string, when time.Time, path, mime, caption string) *Event {
+	return &Event{
+		Event:   EventFileMessage,
+		From:    from,
+		Nick:    nick,
+		Time:    when.Format(time.RFC3339),
+		Path:    path,
+		Mime:    mime,
+		Caption: caption,
+	}
+}
+
 // NewErrorEvent creates an error event
 func NewErrorEvent(err error) *Event {
 	return &Event{
@@ -87,6 +136,39 @@ func NewPongEvent() *Event {
 	}
 }
 
+// NewLookupResultEvent creates a lookup_result event
+func NewLookupResultEvent(threemaID, pubkey string) *Event {
+	return &Event{
+		Event:     EventLookupResult,
+		ThreemaID: threemaID,
+		Pubkey:    pubkey,
+	}
+}
+
+// NewTrustChangedEvent creates a trust_changed event, emitted the first time
+// a Threema ID's public key is recorded by the trust store (TOFU) or when a
+// conflicting key is explicitly overridden via the trust command.
+func NewTrustChangedEvent(threemaID, pubkey string) *Event {
+	return &Event{
+		Event:     EventTrustChanged,
+		ThreemaID: threemaID,
+		Pubkey:    pubkey,
+	}
+}
+
+// NewTrustConflictEvent creates a trust_conflict event, emitted when an
+// incoming public key for a Threema ID disagrees with the key already
+// recorded in the trust store. Sends to that ID are blocked until an
+// explicit trust command resolves the conflict.
+func NewTrustConflictEvent(threemaID, pubkey, prevPubkey string) *Event {
+	return &Event{
+		Event:      EventTrustConflict,
+		ThreemaID:  threemaID,
+		Pubkey:     pubkey,
+		PrevPubkey: prevPubkey,
+	}
+}
+
 // ToJSON serializes an event to JSON
 func (e *Event) ToJSON() ([]byte, error) {
 	return json.Marshal(e)
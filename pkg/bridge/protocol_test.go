@@ -139,6 +139,69 @@ func TestNewErrorEvent(t *testing.T) {
 	}
 }
 
+func TestNewFileMessageEvent(t *testing.T) {
+	when := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	event := NewFileMessageEvent("SENDER01", "Alice", when, "/tmp/file123", "image/png", "a photo")
+
+	if event.Event != EventFileMessage {
+		t.Errorf("Event = %v, want %v", event.Event, EventFileMessage)
+	}
+	if event.Path != "/tmp/file123" {
+		t.Errorf("Path = %v, want /tmp/file123", event.Path)
+	}
+	if event.Mime != "image/png" {
+		t.Errorf("Mime = %v, want image/png", event.Mime)
+	}
+	if event.Caption != "a photo" {
+		t.Errorf("Caption = %v, want a photo", event.Caption)
+	}
+}
+
+func TestNewLookupResultEvent(t *testing.T) {
+	event := NewLookupResultEvent("ABCD1234", "base64key==")
+
+	if event.Event != EventLookupResult {
+		t.Errorf("Event = %v, want %v", event.Event, EventLookupResult)
+	}
+	if event.ThreemaID != "ABCD1234" {
+		t.Errorf("ThreemaID = %v, want ABCD1234", event.ThreemaID)
+	}
+	if event.Pubkey != "base64key==" {
+		t.Errorf("Pubkey = %v, want base64key==", event.Pubkey)
+	}
+}
+
+func TestNewTrustChangedEvent(t *testing.T) {
+	event := NewTrustChangedEvent("ABCD1234", "base64key==")
+
+	if event.Event != EventTrustChanged {
+		t.Errorf("Event = %v, want %v", event.Event, EventTrustChanged)
+	}
+	if event.ThreemaID != "ABCD1234" {
+		t.Errorf("ThreemaID = %v, want ABCD1234", event.ThreemaID)
+	}
+	if event.Pubkey != "base64key==" {
+		t.Errorf("Pubkey = %v, want base64key==", event.Pubkey)
+	}
+}
+
+func TestNewTrustConflictEvent(t *testing.T) {
+	event := NewTrustConflictEvent("ABCD1234", "newkey==", "oldkey==")
+
+	if event.Event != EventTrustConflict {
+		t.Errorf("Event = %v, want %v", event.Event, EventTrustConflict)
+	}
+	if event.ThreemaID != "ABCD1234" {
+		t.Errorf("ThreemaID = %v, want ABCD1234", event.ThreemaID)
+	}
+	if event.Pubkey != "newkey==" {
+		t.Errorf("Pubkey = %v, want newkey==", event.Pubkey)
+	}
+	if event.PrevPubkey != "oldkey==" {
+		t.Errorf("PrevPubkey = %v, want oldkey==", event.PrevPubkey)
+	}
+}
+
 func TestEventToJSON(t *testing.T) {
 	event := NewPongEvent()
 	data, err := event.ToJSON()
@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// HandlerFunc handles one JSON-RPC method call. Params is the raw
+// "params" object from the request; handlers decode it into their own
+// method-specific type (see params.go).
+type HandlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server dispatches JSON-RPC 2.0 requests to registered method handlers. It
+// guarantees exactly one Response per Request (barring true notifications,
+// which have no id and get none), even if a handler panics.
+type Server struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewServer creates an empty Server; use Register to wire up methods.
+func NewServer() *Server {
+	return &Server{handlers: make(map[string]HandlerFunc)}
+}
+
+// Register associates a method name with its handler. Registering the same
+// method twice replaces the previous handler.
+func (s *Server) Register(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Dispatch parses and invokes a single request line, returning the
+// Response to send back. It returns nil only when the incoming line was a
+// well-formed notification (no id), since those expect no reply.
+func (s *Server) Dispatch(ctx context.Context, line []byte) *Response {
+	req, err := ParseRequest(line)
+	if err != nil {
+		return NewErrorResponse(nil, CodeParseError, fmt.Sprintf("invalid JSON-RPC request: %v", err))
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[req.Method]
+	s.mu.RUnlock()
+	if !ok {
+		if req.IsNotification() {
+			return nil
+		}
+		return NewErrorResponse(req.ID, CodeMethodNotFound, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+
+	result, err := s.invoke(ctx, handler, req.Params)
+	if req.IsNotification() {
+		return nil
+	}
+	if err != nil {
+		if rpcErr, ok := err.(*RPCError); ok {
+			return NewErrorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+		}
+		return NewErrorResponse(req.ID, CodeInternalError, err.Error())
+	}
+	return NewResponse(req.ID, result)
+}
+
+// invoke calls handler, converting a panic into an internal error so a
+// single misbehaving handler can never leave a request without a response.
+func (s *Server) invoke(ctx context.Context, handler HandlerFunc, params json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, params)
+}
@@ -0,0 +1,79 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestServerDispatchSuccess(t *testing.T) {
+	s := NewServer()
+	s.Register(CmdPing, func(ctx context.Context, params json.RawMessage) (any, error) {
+		return AckResult{OK: true}, nil
+	})
+
+	resp := s.Dispatch(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	if resp == nil {
+		t.Fatal("Dispatch() = nil, want a Response for a request with an id")
+	}
+	if resp.Error != nil {
+		t.Fatalf("Error = %+v, want nil", resp.Error)
+	}
+	if string(resp.ID) != "1" {
+		t.Errorf("ID = %s, want 1", resp.ID)
+	}
+}
+
+func TestServerDispatchUnknownMethod(t *testing.T) {
+	s := NewServer()
+	resp := s.Dispatch(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"nope"}`))
+	if resp == nil || resp.Error == nil {
+		t.Fatal("Dispatch() expected an error response for an unknown method")
+	}
+	if resp.Error.Code != CodeMethodNotFound {
+		t.Errorf("Code = %d, want %d", resp.Error.Code, CodeMethodNotFound)
+	}
+}
+
+func TestServerDispatchInvalidJSON(t *testing.T) {
+	s := NewServer()
+	resp := s.Dispatch(context.Background(), []byte(`{not valid}`))
+	if resp == nil || resp.Error == nil {
+		t.Fatal("Dispatch() expected an error response for invalid JSON")
+	}
+	if resp.Error.Code != CodeParseError {
+		t.Errorf("Code = %d, want %d", resp.Error.Code, CodeParseError)
+	}
+}
+
+func TestServerDispatchNotificationGetsNoResponse(t *testing.T) {
+	s := NewServer()
+	called := false
+	s.Register(CmdPing, func(ctx context.Context, params json.RawMessage) (any, error) {
+		called = true
+		return AckResult{OK: true}, nil
+	})
+
+	resp := s.Dispatch(context.Background(), []byte(`{"jsonrpc":"2.0","method":"ping"}`))
+	if resp != nil {
+		t.Errorf("Dispatch() = %+v, want nil for a notification", resp)
+	}
+	if !called {
+		t.Error("expected the handler to still run for a notification")
+	}
+}
+
+func TestServerDispatchHandlerPanicRecovered(t *testing.T) {
+	s := NewServer()
+	s.Register(CmdPing, func(ctx context.Context, params json.RawMessage) (any, error) {
+		panic("boom")
+	})
+
+	resp := s.Dispatch(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"ping"}`))
+	if resp == nil || resp.Error == nil {
+		t.Fatal("Dispatch() expected an error response when the handler panics")
+	}
+	if resp.Error.Code != CodeInternalError {
+		t.Errorf("Code = %d, want %d", resp.Error.Code, CodeInternalError)
+	}
+}
@@ -0,0 +1,20 @@
+package events
+
+import "context"
+
+// Chain fans an audit event out to every Emitter in the list. It is itself
+// an Emitter, so `main` can build one from CLI flags and hand it to Bridge
+// as a single value.
+type Chain []Emitter
+
+// EmitAuditEvent implements Emitter, emitting to every backend in the chain
+// and returning the first error encountered (after attempting the rest).
+func (c Chain) EmitAuditEvent(ctx context.Context, event AuditEvent) error {
+	var firstErr error
+	for _, e := range c {
+		if err := e.EmitAuditEvent(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
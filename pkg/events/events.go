@@ -0,0 +1,45 @@
+// Package events defines a structured audit trail for the Threema bridge,
+// independent of the JSON protocol the bridge speaks to its TypeScript
+// plugin over stdout. Every meaningful bridge action (connect, reconnect,
+// send, trust, incoming message, spam drop, error, shutdown) produces an
+// AuditEvent that is fanned out to one or more pluggable Emitter backends,
+// giving operators a durable, greppable record regardless of what the
+// plugin does with stdout.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Audit event codes. These are stable strings so they can be grepped or
+// filtered on by downstream log tooling.
+const (
+	CodeConnect          = "connect"
+	CodeReconnectAttempt = "reconnect_attempt"
+	CodeReconnectSuccess = "reconnect_success"
+	CodeReconnectFailure = "reconnect_failure"
+	CodeSend             = "send"
+	CodeTrust            = "trust"
+	CodeMessage          = "message"
+	CodeSpamDrop         = "spam_drop"
+	CodeError            = "error"
+	CodeShutdown         = "shutdown"
+)
+
+// AuditEvent is a single, typed entry in the audit trail.
+type AuditEvent struct {
+	Time      time.Time `json:"time"`
+	Code      string    `json:"code"`
+	ThreemaID string    `json:"threemaId,omitempty"` // Own identity, where relevant
+	Peer      string    `json:"peer,omitempty"`      // Remote Threema ID, where relevant
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Emitter delivers audit events to a backend. Implementations must be safe
+// for concurrent use, since bridge handlers may emit from multiple
+// goroutines (reconnect loop, stdin read loop, etc).
+type Emitter interface {
+	EmitAuditEvent(ctx context.Context, event AuditEvent) error
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscard(t *testing.T) {
+	if err := (Discard{}).EmitAuditEvent(context.Background(), AuditEvent{Code: CodeConnect}); err != nil {
+		t.Errorf("EmitAuditEvent() error = %v, want nil", err)
+	}
+}
+
+func TestStdoutEmitsJSONLine(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewStdoutEmitterTo(&buf)
+
+	event := AuditEvent{
+		Time:      time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC),
+		Code:      CodeSend,
+		ThreemaID: "MYID1234",
+		Peer:      "ABCD1234",
+		Success:   true,
+	}
+	if err := e.EmitAuditEvent(context.Background(), event); err != nil {
+		t.Fatalf("EmitAuditEvent() error = %v", err)
+	}
+
+	var got AuditEvent
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode emitted line: %v", err)
+	}
+	if got != event {
+		t.Errorf("got %+v, want %+v", got, event)
+	}
+}
+
+func TestFileLogAppendsAndRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	f, err := NewFileLog(path)
+	if err != nil {
+		t.Fatalf("NewFileLog() error = %v", err)
+	}
+	f.maxSizeBytes = 1 // force rotation on the very first event
+	defer f.Close()
+
+	if err := f.EmitAuditEvent(context.Background(), AuditEvent{Code: CodeShutdown}); err != nil {
+		t.Fatalf("EmitAuditEvent() error = %v", err)
+	}
+	if err := f.EmitAuditEvent(context.Background(), AuditEvent{Code: CodeShutdown}); err != nil {
+		t.Fatalf("EmitAuditEvent() error = %v", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected rotated backup %s.1 to exist: %v", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read active log: %v", err)
+	}
+	if !strings.Contains(string(data), CodeShutdown) {
+		t.Errorf("active log missing expected event: %s", data)
+	}
+}
+
+func TestChainFansOutToAllEmitters(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	chain := Chain{NewStdoutEmitterTo(&buf1), NewStdoutEmitterTo(&buf2)}
+
+	if err := chain.EmitAuditEvent(context.Background(), AuditEvent{Code: CodeConnect}); err != nil {
+		t.Fatalf("EmitAuditEvent() error = %v", err)
+	}
+	if buf1.Len() == 0 || buf2.Len() == 0 {
+		t.Errorf("expected both emitters in the chain to receive the event")
+	}
+}
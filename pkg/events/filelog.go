@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// defaultMaxSizeBytes is the size threshold at which FileLog rotates the
+// active log file before it would otherwise grow unbounded.
+const defaultMaxSizeBytes = 10 * 1024 * 1024 // 10 MiB
+
+// defaultMaxBackups is how many rotated files (path.1, path.2, ...) FileLog
+// keeps around before the oldest is discarded.
+const defaultMaxBackups = 5
+
+// FileLog is an Emitter that appends audit events as JSON lines to a file
+// on disk, rotating it before a write would take it past maxSizeBytes.
+type FileLog struct {
+	mu           sync.Mutex
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+	file         *os.File
+	size         int64
+}
+
+// NewFileLog opens (or creates) a rotating audit log at path.
+func NewFileLog(path string) (*FileLog, error) {
+	f := &FileLog{
+		path:         path,
+		maxSizeBytes: defaultMaxSizeBytes,
+		maxBackups:   defaultMaxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileLog) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log %s: %w", f.path, err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// EmitAuditEvent implements Emitter.
+func (f *FileLog) EmitAuditEvent(ctx context.Context, event AuditEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	data = append(data, '\n')
+
+	if f.size+int64(len(data)) > f.maxSizeBytes {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the current file, shifts path.N -> path.N+1 (dropping
+// anything beyond maxBackups), and opens a fresh file. Caller must hold f.mu.
+func (f *FileLog) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log for rotation: %w", err)
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", f.path, i)
+		dst := fmt.Sprintf("%s.%d", f.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if _, err := os.Stat(f.path); err == nil {
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	return f.open()
+}
+
+// Close closes the underlying file.
+func (f *FileLog) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
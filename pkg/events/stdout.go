@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Stdout is an Emitter that writes each audit event as a single line of
+// JSON to the given writer (os.Stderr by default). The name is historical;
+// the bridge's stdout is the JSON-RPC protocol stream the TS plugin parses
+// (see cmd/threema-bridge's writeLoop), so this emitter must not write
+// there itself or audit lines would corrupt that stream. It writes to
+// stderr instead, giving a durable, greppable record independent of
+// whatever the plugin does with stdout.
+type Stdout struct {
+	mu  sync.Mutex
+	out io.Writer
+	enc *json.Encoder
+}
+
+// NewStdoutEmitter creates a Stdout emitter writing to os.Stderr.
+func NewStdoutEmitter() *Stdout {
+	return NewStdoutEmitterTo(os.Stderr)
+}
+
+// NewStdoutEmitterTo creates a Stdout emitter writing to an arbitrary writer,
+// primarily so tests can assert on the emitted JSON.
+func NewStdoutEmitterTo(w io.Writer) *Stdout {
+	return &Stdout{out: w, enc: json.NewEncoder(w)}
+}
+
+// EmitAuditEvent implements Emitter.
+func (s *Stdout) EmitAuditEvent(ctx context.Context, event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(event)
+}
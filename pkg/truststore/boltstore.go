@@ -0,0 +1,89 @@
+package truststore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func init() {
+	Register("bolt", func(dsn string) (Store, error) { return newBoltStore(dsn) })
+}
+
+var trustBucket = []byte("trust")
+
+// boltStore persists trust entries in a single-file bbolt database,
+// useful once a file-per-write JSON store gets too large or too hot.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt trust store %s: %w", path, err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(trustBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init bolt trust store: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (b *boltStore) Get(id string) (Entry, error) {
+	var entry Entry
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(trustBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &entry)
+	})
+	return entry, err
+}
+
+func (b *boltStore) Put(id, pubkey string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(trustBucket)
+
+		firstSeen := time.Now()
+		if existing := bucket.Get([]byte(id)); existing != nil {
+			var prev Entry
+			if err := json.Unmarshal(existing, &prev); err == nil {
+				firstSeen = prev.FirstSeen
+			}
+		}
+
+		data, err := json.Marshal(Entry{ThreemaID: id, Pubkey: pubkey, FirstSeen: firstSeen})
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), data)
+	})
+}
+
+func (b *boltStore) List() ([]Entry, error) {
+	var entries []Entry
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(trustBucket).ForEach(func(k, v []byte) error {
+			var entry Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	return entries, err
+}
+
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,93 @@
+package truststore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("file", func(dsn string) (Store, error) { return newFileStore(dsn) })
+}
+
+// fileStore is the default Store backend: a JSON file on disk, rewritten
+// in full on every Put. Fine for a single bridge instance's worth of
+// contacts; boltStore exists for anything bigger.
+type fileStore struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+}
+
+func newFileStore(path string) (*fileStore, error) {
+	f := &fileStore{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust store %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &f.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse trust store %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (f *fileStore) Get(id string) (Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.entries[id]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (f *fileStore) Put(id, pubkey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	firstSeen := time.Now()
+	if existing, ok := f.entries[id]; ok {
+		firstSeen = existing.FirstSeen
+	}
+	f.entries[id] = Entry{ThreemaID: id, Pubkey: pubkey, FirstSeen: firstSeen}
+
+	return f.persist()
+}
+
+func (f *fileStore) List() ([]Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries := make([]Entry, 0, len(f.entries))
+	for _, entry := range f.entries {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (f *fileStore) Close() error {
+	return nil
+}
+
+// persist writes the full entry set to disk. Caller must hold f.mu.
+func (f *fileStore) persist() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create trust store dir: %w", err)
+	}
+	data, err := json.Marshal(f.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trust store: %w", err)
+	}
+	if err := os.WriteFile(f.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write trust store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package truststore
+
+import "fmt"
+
+// Factory constructs a Store from a backend-specific DSN (a file path for
+// "file"/"bolt"). Backends register themselves under a name via Register,
+// typically from an init() in their own file, so adding a backend never
+// touches this file.
+type Factory func(dsn string) (Store, error)
+
+var registry = map[string]Factory{}
+
+// Register adds a backend factory under name, overwriting any previous
+// registration for that name. Call from an init() func.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// Open constructs a Store using the backend registered under name.
+func Open(name, dsn string) (Store, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("truststore: unknown backend %q", name)
+	}
+	return factory(dsn)
+}
@@ -0,0 +1,35 @@
+// Package truststore persists Threema ID -> public key trust relationships
+// across bridge restarts, for trust-on-first-use (TOFU): whichever key is
+// first seen for an ID is recorded. The store does not detect conflicts
+// itself; Put overwrites the stored key unconditionally, so callers check
+// an incoming key against Get before deciding whether to Put it.
+package truststore
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when no entry exists for a Threema ID.
+var ErrNotFound = errors.New("truststore: not found")
+
+// Entry is a trusted contact's public key and when it was first recorded.
+type Entry struct {
+	ThreemaID string
+	Pubkey    string
+	FirstSeen time.Time
+}
+
+// Store persists trust relationships. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the entry for id, or ErrNotFound if none exists.
+	Get(id string) (Entry, error)
+	// Put records pubkey as the trusted key for id, preserving the
+	// original FirstSeen if an entry already exists.
+	Put(id, pubkey string) error
+	// List returns every trusted entry, in no particular order.
+	List() ([]Entry, error)
+	// Close releases any resources held by the store.
+	Close() error
+}
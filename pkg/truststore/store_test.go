@@ -0,0 +1,123 @@
+package truststore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStoreGetPutRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.json")
+	store, err := newFileStore(path)
+	if err != nil {
+		t.Fatalf("newFileStore() error = %v", err)
+	}
+
+	if _, err := store.Get("ABCD1234"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() error = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Put("ABCD1234", "pubkey=="); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	entry, err := store.Get("ABCD1234")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if entry.Pubkey != "pubkey==" {
+		t.Errorf("Pubkey = %v, want pubkey==", entry.Pubkey)
+	}
+	if entry.FirstSeen.IsZero() {
+		t.Error("FirstSeen = zero value, want a recorded timestamp")
+	}
+}
+
+func TestFileStorePutPreservesFirstSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.json")
+	store, err := newFileStore(path)
+	if err != nil {
+		t.Fatalf("newFileStore() error = %v", err)
+	}
+
+	if err := store.Put("ABCD1234", "key-a"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	first, _ := store.Get("ABCD1234")
+
+	if err := store.Put("ABCD1234", "key-b"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	second, err := store.Get("ABCD1234")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if second.Pubkey != "key-b" {
+		t.Errorf("Pubkey = %v, want key-b", second.Pubkey)
+	}
+	if !second.FirstSeen.Equal(first.FirstSeen) {
+		t.Errorf("FirstSeen changed on overwrite: %v -> %v", first.FirstSeen, second.FirstSeen)
+	}
+}
+
+func TestFileStorePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.json")
+
+	store, err := newFileStore(path)
+	if err != nil {
+		t.Fatalf("newFileStore() error = %v", err)
+	}
+	if err := store.Put("ABCD1234", "pubkey=="); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	reopened, err := newFileStore(path)
+	if err != nil {
+		t.Fatalf("newFileStore() (reopen) error = %v", err)
+	}
+	entry, err := reopened.Get("ABCD1234")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if entry.Pubkey != "pubkey==" {
+		t.Errorf("Pubkey = %v, want pubkey== after reopen", entry.Pubkey)
+	}
+}
+
+func TestFileStoreList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.json")
+	store, err := newFileStore(path)
+	if err != nil {
+		t.Fatalf("newFileStore() error = %v", err)
+	}
+	store.Put("ABCD1234", "key-a")
+	store.Put("EFGH5678", "key-b")
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("List() returned %d entries, want 2", len(entries))
+	}
+}
+
+func TestOpenUnknownBackend(t *testing.T) {
+	if _, err := Open("nonexistent", "whatever"); err == nil {
+		t.Error("Open() expected an error for an unregistered backend")
+	}
+}
+
+func TestOpenFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trust.json")
+	store, err := Open("file", path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Put("ABCD1234", "pubkey=="); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+}